@@ -0,0 +1,79 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"github.com/blugelabs/bluge/geo/shape"
+)
+
+// GeoShapeField is the indexing-side counterpart of
+// searcher.NewGeoShapeSearcher: it tiles a shape into quadtree covering
+// cell terms at Precision (searcher.GeoShapePrecision must match) and
+// stores the shape's WKB encoding as this field's doc value, so the
+// searcher can first narrow candidates via the cell terms and then
+// evaluate the precise relation against the decoded WKB.
+type GeoShapeField struct {
+	name      string
+	shape     shape.Shape
+	precision uint
+
+	// leafCell is set only for a field built by NewGeoPointShapeField,
+	// where the shape is a single point and its one covering cell is
+	// already known without walking the quadtree.
+	leafCell string
+}
+
+// NewGeoShapeField returns a field that indexes s under name, tiled into
+// covering cell terms at precision.
+func NewGeoShapeField(name string, s shape.Shape, precision uint) *GeoShapeField {
+	return &GeoShapeField{name: name, shape: s, precision: precision}
+}
+
+// NewGeoPointShapeField indexes a single (lon, lat) point as a
+// degenerate one-cell GeoShape, so a field carrying point data can also
+// be matched through NewGeoShapeSearcher.
+func NewGeoPointShapeField(name string, lon, lat float64, precision uint) *GeoShapeField {
+	return &GeoShapeField{
+		name:      name,
+		shape:     &shape.Envelope{MinLon: lon, MinLat: lat, MaxLon: lon, MaxLat: lat},
+		precision: precision,
+		leafCell:  shape.CoveringCellsForPoint(lon, lat, precision),
+	}
+}
+
+// Name returns the field name.
+func (f *GeoShapeField) Name() string { return f.name }
+
+// Terms returns the covering cell tokens to index as terms for this
+// field's shape.
+func (f *GeoShapeField) Terms() [][]byte {
+	if f.leafCell != "" {
+		return [][]byte{[]byte(f.leafCell)}
+	}
+
+	cells := shape.CoveringCells(f.shape, f.precision)
+	terms := make([][]byte, len(cells))
+	for i, c := range cells {
+		terms[i] = []byte(c)
+	}
+	return terms
+}
+
+// Value returns the WKB encoding of the shape, stored as this field's
+// doc value so NewGeoShapeSearcher's filter can evaluate the exact
+// relation at query time.
+func (f *GeoShapeField) Value() []byte {
+	return shape.EncodeWKB(f.shape)
+}
@@ -0,0 +1,54 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"testing"
+
+	"github.com/blugelabs/bluge/geo/shape"
+)
+
+func TestGeoShapeFieldIndexesTermsAndDocValue(t *testing.T) {
+	poly := &shape.Polygon{Outer: []shape.Point{
+		{Lon: 0, Lat: 0}, {Lon: 0, Lat: 1}, {Lon: 1, Lat: 1}, {Lon: 1, Lat: 0}, {Lon: 0, Lat: 0},
+	}}
+
+	f := NewGeoShapeField("shape", poly, 16)
+
+	terms := f.Terms()
+	if len(terms) == 0 {
+		t.Fatal("expected at least one covering cell term")
+	}
+
+	decoded, err := shape.DecodeWKB(f.Value())
+	if err != nil {
+		t.Fatalf("unexpected error decoding doc value: %v", err)
+	}
+	if decoded.Type() != "Polygon" {
+		t.Fatalf("expected decoded shape type Polygon, got %s", decoded.Type())
+	}
+}
+
+func TestGeoPointShapeFieldIndexesSingleLeafCell(t *testing.T) {
+	f := NewGeoPointShapeField("shape", 12.5, -4.25, 20)
+
+	terms := f.Terms()
+	if len(terms) != 1 {
+		t.Fatalf("expected exactly one covering cell for a point, got %d", len(terms))
+	}
+	if len(terms[0]) != 20 {
+		t.Fatalf("expected a depth-20 leaf token, got %q", terms[0])
+	}
+}
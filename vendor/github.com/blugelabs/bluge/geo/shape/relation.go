@@ -0,0 +1,162 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shape
+
+// Relate reports whether indexed relates to query per rel. It is the
+// precise, doc-value-driven predicate evaluated after the covering-cell
+// candidate set has narrowed the search down to plausible documents.
+func Relate(indexed, query Shape, rel Relation) bool {
+	switch rel {
+	case Intersects:
+		return intersects(indexed, query)
+	case Disjoint:
+		return !intersects(indexed, query)
+	case Within:
+		return covers(query, indexed)
+	case Contains:
+		return covers(indexed, query)
+	default:
+		return false
+	}
+}
+
+// covers reports whether every ring of inner lies within outer, using
+// vertex containment plus an edge-crossing check so that a ring which
+// pokes outside outer between its vertices is not mistakenly accepted.
+func covers(outer, inner Shape) bool {
+	oMinLon, oMinLat, oMaxLon, oMaxLat := outer.BoundingBox()
+	iMinLon, iMinLat, iMaxLon, iMaxLat := inner.BoundingBox()
+	if !overlaps(iMinLon, iMinLat, iMaxLon, iMaxLat, oMinLon, oMinLat, oMaxLon, oMaxLat) ||
+		iMinLon < oMinLon || iMinLat < oMinLat || iMaxLon > oMaxLon || iMaxLat > oMaxLat {
+		return false
+	}
+
+	for _, ring := range inner.Coordinates() {
+		for _, p := range ring {
+			if !containsPoint(outer, p) {
+				return false
+			}
+		}
+	}
+	return !edgesCross(outer, inner)
+}
+
+func intersects(a, b Shape) bool {
+	aMinLon, aMinLat, aMaxLon, aMaxLat := a.BoundingBox()
+	bMinLon, bMinLat, bMaxLon, bMaxLat := b.BoundingBox()
+	if !overlaps(aMinLon, aMinLat, aMaxLon, aMaxLat, bMinLon, bMinLat, bMaxLon, bMaxLat) {
+		return false
+	}
+
+	for _, ring := range a.Coordinates() {
+		if len(ring) > 0 && containsPoint(b, ring[0]) {
+			return true
+		}
+	}
+	for _, ring := range b.Coordinates() {
+		if len(ring) > 0 && containsPoint(a, ring[0]) {
+			return true
+		}
+	}
+	return edgesCross(a, b)
+}
+
+// containsPoint tests p against shape's outer ring via ray casting,
+// honoring holes for Polygon/MultiPolygon. An open shape (a LineString)
+// has no interior, so it never contains a point.
+func containsPoint(s Shape, p Point) bool {
+	if !s.Closed() {
+		return false
+	}
+	rings := s.Coordinates()
+	if len(rings) == 0 {
+		return false
+	}
+	if !rayCast(rings[0], p) {
+		return false
+	}
+	for _, hole := range rings[1:] {
+		if rayCast(hole, p) {
+			return false
+		}
+	}
+	return true
+}
+
+func rayCast(ring []Point, p Point) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		pi, pj := ring[i], ring[j]
+		if (pi.Lat > p.Lat) != (pj.Lat > p.Lat) &&
+			p.Lon < (pj.Lon-pi.Lon)*(p.Lat-pi.Lat)/(pj.Lat-pi.Lat)+pi.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func edgesCross(a, b Shape) bool {
+	aClosed, bClosed := a.Closed(), b.Closed()
+	for _, ringA := range a.Coordinates() {
+		for _, ringB := range b.Coordinates() {
+			if ringsCross(ringA, aClosed, ringB, bClosed) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ringsCross tests every edge of a against every edge of b. An open
+// LineString ring has no edge connecting its last point back to its
+// first, unlike a closed ring (Polygon/MultiPolygon/Envelope) - treating
+// it as closed would test a phantom edge that was never indexed.
+func ringsCross(a []Point, aClosed bool, b []Point, bClosed bool) bool {
+	for i := 0; i < ringEdgeCount(a, aClosed); i++ {
+		a1, a2 := a[i], a[(i+1)%len(a)]
+		for j := 0; j < ringEdgeCount(b, bClosed); j++ {
+			b1, b2 := b[j], b[(j+1)%len(b)]
+			if segmentsIntersect(a1, a2, b1, b2) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ringEdgeCount returns the number of edges in ring: len(ring) for a
+// closed ring (including the wraparound edge back to the first point),
+// or len(ring)-1 for an open polyline.
+func ringEdgeCount(ring []Point, closed bool) int {
+	if closed {
+		return len(ring)
+	}
+	if len(ring) == 0 {
+		return 0
+	}
+	return len(ring) - 1
+}
+
+func segmentsIntersect(p1, p2, p3, p4 Point) bool {
+	d1 := cross(p3, p4, p1)
+	d2 := cross(p3, p4, p2)
+	d3 := cross(p1, p2, p3)
+	d4 := cross(p1, p2, p4)
+	return ((d1 > 0) != (d2 > 0)) && ((d3 > 0) != (d4 > 0))
+}
+
+func cross(o, a, b Point) float64 {
+	return (a.Lon-o.Lon)*(b.Lat-o.Lat) - (a.Lat-o.Lat)*(b.Lon-o.Lon)
+}
@@ -0,0 +1,126 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shape
+
+import "testing"
+
+func square() *Polygon {
+	return &Polygon{Outer: []Point{
+		{Lon: 0, Lat: 0}, {Lon: 0, Lat: 10}, {Lon: 10, Lat: 10}, {Lon: 10, Lat: 0}, {Lon: 0, Lat: 0},
+	}}
+}
+
+func TestRayCastInteriorAndExteriorPoints(t *testing.T) {
+	ring := square().Outer
+	if !rayCast(ring, Point{Lon: 5, Lat: 5}) {
+		t.Fatal("expected interior point to be contained")
+	}
+	if rayCast(ring, Point{Lon: 20, Lat: 20}) {
+		t.Fatal("expected exterior point to be outside")
+	}
+}
+
+func TestContainsPointHonorsHoles(t *testing.T) {
+	p := &Polygon{
+		Outer: square().Outer,
+		Holes: [][]Point{{
+			{Lon: 4, Lat: 4}, {Lon: 4, Lat: 6}, {Lon: 6, Lat: 6}, {Lon: 6, Lat: 4}, {Lon: 4, Lat: 4},
+		}},
+	}
+	if !containsPoint(p, Point{Lon: 1, Lat: 1}) {
+		t.Fatal("expected point outside the hole to be contained")
+	}
+	if containsPoint(p, Point{Lon: 5, Lat: 5}) {
+		t.Fatal("expected point inside the hole to not be contained")
+	}
+}
+
+func TestContainsPointLineStringHasNoInterior(t *testing.T) {
+	l := &LineString{Points: []Point{{Lon: 0, Lat: 0}, {Lon: 10, Lat: 10}}}
+	if containsPoint(l, Point{Lon: 5, Lat: 5}) {
+		t.Fatal("expected an open LineString to never contain a point")
+	}
+}
+
+// TestIntersectsLineStringDoesNotUseClosingEdge guards against treating an
+// open LineString as an implicitly closed ring: an "L" shaped line from
+// (0,0) to (0,10) to (10,10) only has those two real segments. A polygon
+// that sits only near the synthetic diagonal from (10,10) back to (0,0)
+// (which a closed-ring treatment would fabricate) must not be reported as
+// intersecting.
+func TestIntersectsLineStringDoesNotUseClosingEdge(t *testing.T) {
+	line := &LineString{Points: []Point{{Lon: 0, Lat: 0}, {Lon: 0, Lat: 10}, {Lon: 10, Lat: 10}}}
+
+	// sits astride the phantom (10,10)->(0,0) diagonal, far from either
+	// real segment of the line
+	near := &Polygon{Outer: []Point{
+		{Lon: 4, Lat: 3}, {Lon: 4, Lat: 5}, {Lon: 6, Lat: 5}, {Lon: 6, Lat: 3}, {Lon: 4, Lat: 3},
+	}}
+
+	if intersects(line, near) {
+		t.Fatal("expected no intersection through a phantom closing edge on an open LineString")
+	}
+
+	// a polygon that actually straddles the real vertical segment
+	crossing := &Polygon{Outer: []Point{
+		{Lon: -1, Lat: 3}, {Lon: -1, Lat: 5}, {Lon: 1, Lat: 5}, {Lon: 1, Lat: 3}, {Lon: -1, Lat: 3},
+	}}
+	if !intersects(line, crossing) {
+		t.Fatal("expected intersection against a polygon straddling a real line segment")
+	}
+}
+
+func TestCoversRequiresFullContainment(t *testing.T) {
+	outer := square()
+	inner := &Polygon{Outer: []Point{
+		{Lon: 2, Lat: 2}, {Lon: 2, Lat: 4}, {Lon: 4, Lat: 4}, {Lon: 4, Lat: 2}, {Lon: 2, Lat: 2},
+	}}
+	if !covers(outer, inner) {
+		t.Fatal("expected outer to cover an inner polygon fully inside it")
+	}
+
+	straddling := &Polygon{Outer: []Point{
+		{Lon: -5, Lat: 2}, {Lon: -5, Lat: 4}, {Lon: 4, Lat: 4}, {Lon: 4, Lat: 2}, {Lon: -5, Lat: 2},
+	}}
+	if covers(outer, straddling) {
+		t.Fatal("expected outer to not cover a polygon that pokes outside its bounds")
+	}
+}
+
+func TestRelateIntersectsWithinContainsDisjoint(t *testing.T) {
+	outer := square()
+	inner := &Polygon{Outer: []Point{
+		{Lon: 2, Lat: 2}, {Lon: 2, Lat: 4}, {Lon: 4, Lat: 4}, {Lon: 4, Lat: 2}, {Lon: 2, Lat: 2},
+	}}
+	elsewhere := &Polygon{Outer: []Point{
+		{Lon: 100, Lat: 100}, {Lon: 100, Lat: 101}, {Lon: 101, Lat: 101}, {Lon: 101, Lat: 100}, {Lon: 100, Lat: 100},
+	}}
+
+	if !Relate(inner, outer, Within) {
+		t.Fatal("expected inner to be Within outer")
+	}
+	if !Relate(outer, inner, Contains) {
+		t.Fatal("expected outer to Contain inner")
+	}
+	if !Relate(outer, inner, Intersects) {
+		t.Fatal("expected outer and inner to Intersect")
+	}
+	if !Relate(outer, elsewhere, Disjoint) {
+		t.Fatal("expected outer and elsewhere to be Disjoint")
+	}
+	if Relate(outer, elsewhere, Intersects) {
+		t.Fatal("expected outer and elsewhere to not Intersect")
+	}
+}
@@ -0,0 +1,172 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shape provides GeoShape types (LineString, Polygon, MultiPolygon,
+// Envelope) alongside the quadtree tiling and WKB encoding used to index
+// and query them.
+package shape
+
+import "fmt"
+
+// Relation describes the spatial predicate a GeoShape query tests between
+// the indexed shape and the query shape.
+type Relation int
+
+const (
+	// Intersects matches when the shapes share at least one point.
+	Intersects Relation = iota
+	// Within matches when the indexed shape is entirely inside the query shape.
+	Within
+	// Contains matches when the indexed shape entirely contains the query shape.
+	Contains
+	// Disjoint matches when the shapes share no points.
+	Disjoint
+)
+
+// Point is a single (lon, lat) coordinate.
+type Point struct {
+	Lon, Lat float64
+}
+
+// Shape is implemented by every indexable GeoShape type.
+type Shape interface {
+	// Type identifies the shape for WKB encoding and diagnostics.
+	Type() string
+	// BoundingBox returns the axis-aligned bounding box enclosing the shape.
+	BoundingBox() (minLon, minLat, maxLon, maxLat float64)
+	// Coordinates returns the rings/points making up the shape, outer ring
+	// first, followed by any holes.
+	Coordinates() [][]Point
+	// Closed reports whether the rings returned by Coordinates are closed
+	// (the last point implicitly connects back to the first) or, for an
+	// open LineString, are just a polyline with no such wraparound edge
+	// and no interior.
+	Closed() bool
+}
+
+// LineString is an ordered, open sequence of points.
+type LineString struct {
+	Points []Point
+}
+
+func (l *LineString) Type() string { return "LineString" }
+
+func (l *LineString) BoundingBox() (minLon, minLat, maxLon, maxLat float64) {
+	return boundingBox(l.Points)
+}
+
+func (l *LineString) Coordinates() [][]Point { return [][]Point{l.Points} }
+
+func (l *LineString) Closed() bool { return false }
+
+// Polygon is an outer ring plus zero or more hole rings. Rings are
+// implicitly closed if the caller did not repeat the first point.
+type Polygon struct {
+	Outer []Point
+	Holes [][]Point
+}
+
+func (p *Polygon) Type() string { return "Polygon" }
+
+func (p *Polygon) BoundingBox() (minLon, minLat, maxLon, maxLat float64) {
+	return boundingBox(p.Outer)
+}
+
+func (p *Polygon) Coordinates() [][]Point {
+	rv := make([][]Point, 0, 1+len(p.Holes))
+	rv = append(rv, p.Outer)
+	rv = append(rv, p.Holes...)
+	return rv
+}
+
+func (p *Polygon) Closed() bool { return true }
+
+// MultiPolygon is a collection of independent polygons.
+type MultiPolygon struct {
+	Polygons []*Polygon
+}
+
+func (m *MultiPolygon) Type() string { return "MultiPolygon" }
+
+func (m *MultiPolygon) BoundingBox() (minLon, minLat, maxLon, maxLat float64) {
+	minLon, minLat, maxLon, maxLat = m.Polygons[0].BoundingBox()
+	for _, p := range m.Polygons[1:] {
+		pMinLon, pMinLat, pMaxLon, pMaxLat := p.BoundingBox()
+		minLon, minLat = min(minLon, pMinLon), min(minLat, pMinLat)
+		maxLon, maxLat = max(maxLon, pMaxLon), max(maxLat, pMaxLat)
+	}
+	return minLon, minLat, maxLon, maxLat
+}
+
+func (m *MultiPolygon) Coordinates() [][]Point {
+	var rv [][]Point
+	for _, p := range m.Polygons {
+		rv = append(rv, p.Coordinates()...)
+	}
+	return rv
+}
+
+func (m *MultiPolygon) Closed() bool { return true }
+
+// Envelope is an axis-aligned bounding box shape, as used for simple
+// "within this box" queries.
+type Envelope struct {
+	MinLon, MinLat, MaxLon, MaxLat float64
+}
+
+func (e *Envelope) Type() string { return "Envelope" }
+
+func (e *Envelope) BoundingBox() (minLon, minLat, maxLon, maxLat float64) {
+	return e.MinLon, e.MinLat, e.MaxLon, e.MaxLat
+}
+
+func (e *Envelope) Coordinates() [][]Point {
+	return [][]Point{{
+		{Lon: e.MinLon, Lat: e.MinLat},
+		{Lon: e.MinLon, Lat: e.MaxLat},
+		{Lon: e.MaxLon, Lat: e.MaxLat},
+		{Lon: e.MaxLon, Lat: e.MinLat},
+		{Lon: e.MinLon, Lat: e.MinLat},
+	}}
+}
+
+func (e *Envelope) Closed() bool { return true }
+
+func boundingBox(points []Point) (minLon, minLat, maxLon, maxLat float64) {
+	minLon, maxLon = points[0].Lon, points[0].Lon
+	minLat, maxLat = points[0].Lat, points[0].Lat
+	for _, p := range points[1:] {
+		minLon, maxLon = min(minLon, p.Lon), max(maxLon, p.Lon)
+		minLat, maxLat = min(minLat, p.Lat), max(maxLat, p.Lat)
+	}
+	return minLon, minLat, maxLon, maxLat
+}
+
+func min(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// ErrUnknownShapeType is returned by DecodeWKB for a type code this
+// package does not recognize.
+var ErrUnknownShapeType = fmt.Errorf("unknown geo shape type")
@@ -0,0 +1,103 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shape
+
+// MaxCoveringCells bounds how many cell tokens CoveringCells will ever
+// emit for one shape. Once the budget is exhausted, any cell still being
+// considered is emitted as-is (at whatever depth it reached) rather than
+// descended further, trading a coarser, higher-recall covering for a
+// bounded running time.
+const MaxCoveringCells = 2048
+
+// CoveringCells tiles the world into a quadtree, down to at most
+// precision levels, and returns the token for every cell that overlaps
+// the shape's bounding box. A cell stops descending, and is emitted as a
+// leaf, as soon as it is either fully contained by the shape's bounding
+// box or precision/MaxCoveringCells is reached. The interior of a large
+// shape is thus covered by coarse tokens rather than every leaf cell in
+// it, but cells straddling the edge of the bounding box keep descending
+// to precision. Cells are visited depth-first, so once the budget is
+// spent the cells not yet visited are emitted at the shallow depth they
+// have, while the edge cells visited earlier stay at full precision.
+// Each token is a string of '0'-'3' digits, one per level, naming the
+// path taken through the quadtree (NW=0, NE=1, SW=2, SE=3); it is indexed
+// directly as a term, and a shorter token is a prefix of every finer cell
+// beneath it, so an ancestor's token also matches any descendant cell
+// during search.
+func CoveringCells(s Shape, precision uint) []string {
+	minLon, minLat, maxLon, maxLat := s.BoundingBox()
+	budget := MaxCoveringCells
+	return coveringCells(minLon, minLat, maxLon, maxLat, -180, -90, 180, 90, "", precision, &budget)
+}
+
+func coveringCells(minLon, minLat, maxLon, maxLat,
+	cellMinLon, cellMinLat, cellMaxLon, cellMaxLat float64, path string, levelsLeft uint, budget *int) []string {
+	if !overlaps(minLon, minLat, maxLon, maxLat, cellMinLon, cellMinLat, cellMaxLon, cellMaxLat) {
+		return nil
+	}
+	if levelsLeft == 0 || *budget <= 0 ||
+		contains(minLon, minLat, maxLon, maxLat, cellMinLon, cellMinLat, cellMaxLon, cellMaxLat) {
+		*budget--
+		return []string{path}
+	}
+	*budget--
+
+	midLon := (cellMinLon + cellMaxLon) / 2
+	midLat := (cellMinLat + cellMaxLat) / 2
+
+	var rv []string
+	rv = append(rv, coveringCells(minLon, minLat, maxLon, maxLat,
+		cellMinLon, midLat, midLon, cellMaxLat, path+"0", levelsLeft-1, budget)...) // NW
+	rv = append(rv, coveringCells(minLon, minLat, maxLon, maxLat,
+		midLon, midLat, cellMaxLon, cellMaxLat, path+"1", levelsLeft-1, budget)...) // NE
+	rv = append(rv, coveringCells(minLon, minLat, maxLon, maxLat,
+		cellMinLon, cellMinLat, midLon, midLat, path+"2", levelsLeft-1, budget)...) // SW
+	rv = append(rv, coveringCells(minLon, minLat, maxLon, maxLat,
+		midLon, cellMinLat, cellMaxLon, midLat, path+"3", levelsLeft-1, budget)...) // SE
+	return rv
+}
+
+func overlaps(aMinLon, aMinLat, aMaxLon, aMaxLat, bMinLon, bMinLat, bMaxLon, bMaxLat float64) bool {
+	return aMinLon <= bMaxLon && aMaxLon >= bMinLon && aMinLat <= bMaxLat && aMaxLat >= bMinLat
+}
+
+// contains reports whether the cell (b) lies entirely within the shape's
+// bounding box (a), meaning the cell needs no further subdivision.
+func contains(aMinLon, aMinLat, aMaxLon, aMaxLat, bMinLon, bMinLat, bMaxLon, bMaxLat float64) bool {
+	return aMinLon <= bMinLon && aMinLat <= bMinLat && aMaxLon >= bMaxLon && aMaxLat >= bMaxLat
+}
+
+// CoveringCellsForPoint returns the single leaf cell token containing
+// (lon, lat) at the given precision, used to index a degenerate
+// one-point GeoShape the same way any other shape is tiled.
+func CoveringCellsForPoint(lon, lat float64, precision uint) string {
+	minLon, minLat, maxLon, maxLat := -180.0, -90.0, 180.0, 90.0
+	path := make([]byte, 0, precision)
+	for i := uint(0); i < precision; i++ {
+		midLon := (minLon + maxLon) / 2
+		midLat := (minLat + maxLat) / 2
+		switch {
+		case lon < midLon && lat >= midLat:
+			path, maxLon, minLat = append(path, '0'), midLon, midLat
+		case lon >= midLon && lat >= midLat:
+			path, minLon, minLat = append(path, '1'), midLon, midLat
+		case lon < midLon && lat < midLat:
+			path, maxLon, maxLat = append(path, '2'), midLon, midLat
+		default:
+			path, minLon, maxLat = append(path, '3'), midLon, midLat
+		}
+	}
+	return string(path)
+}
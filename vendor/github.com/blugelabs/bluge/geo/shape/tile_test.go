@@ -0,0 +1,49 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shape
+
+import "testing"
+
+func TestCoveringCellsStopsEarlyForLargeShapes(t *testing.T) {
+	// a shape spanning most of a hemisphere, at a precision fine enough
+	// that naively descending to every leaf would enumerate billions of
+	// cells; the early-stop-on-containment plus budget must keep this
+	// small and fast.
+	e := &Envelope{MinLon: -170, MinLat: -80, MaxLon: 170, MaxLat: 80}
+
+	cells := CoveringCells(e, 24)
+
+	if len(cells) == 0 {
+		t.Fatal("expected at least one covering cell")
+	}
+	if len(cells) > MaxCoveringCells {
+		t.Fatalf("expected covering cells bounded by MaxCoveringCells (%d), got %d",
+			MaxCoveringCells, len(cells))
+	}
+	// a shape this large relative to a leaf cell should stop descending
+	// almost immediately, well short of the requested precision
+	for _, c := range cells {
+		if len(c) >= 24 {
+			t.Fatalf("expected coarse cell tokens for a hemisphere-sized shape, got depth-24 token %q", c)
+		}
+	}
+}
+
+func TestCoveringCellsForPointIsLeafDepth(t *testing.T) {
+	token := CoveringCellsForPoint(10.5, 20.5, 12)
+	if len(token) != 12 {
+		t.Fatalf("expected a 12-character leaf token, got %q", token)
+	}
+}
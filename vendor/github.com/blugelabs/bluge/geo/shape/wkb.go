@@ -0,0 +1,186 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shape
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// well-known binary geometry type codes, as defined by the OGC WKB spec.
+// Envelope has no standard WKB type; it is encoded with a private code.
+const (
+	wkbLineString   uint32 = 2
+	wkbPolygon      uint32 = 3
+	wkbMultiPolygon uint32 = 6
+	wkbEnvelope     uint32 = 0xe4
+)
+
+// EncodeWKB serializes shape to little-endian well-known binary, for
+// storage in a doc value so the precise geometry predicate can be
+// evaluated at query time.
+func EncodeWKB(s Shape) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteByte(1) // NDR (little-endian) byte order marker
+
+	switch v := s.(type) {
+	case *LineString:
+		_ = binary.Write(buf, binary.LittleEndian, wkbLineString)
+		writePoints(buf, v.Points)
+	case *Polygon:
+		_ = binary.Write(buf, binary.LittleEndian, wkbPolygon)
+		writeRings(buf, v.Coordinates())
+	case *MultiPolygon:
+		_ = binary.Write(buf, binary.LittleEndian, wkbMultiPolygon)
+		_ = binary.Write(buf, binary.LittleEndian, uint32(len(v.Polygons)))
+		for _, p := range v.Polygons {
+			_ = binary.Write(buf, binary.LittleEndian, wkbPolygon)
+			writeRings(buf, p.Coordinates())
+		}
+	case *Envelope:
+		_ = binary.Write(buf, binary.LittleEndian, wkbEnvelope)
+		_ = binary.Write(buf, binary.LittleEndian, v.MinLon)
+		_ = binary.Write(buf, binary.LittleEndian, v.MinLat)
+		_ = binary.Write(buf, binary.LittleEndian, v.MaxLon)
+		_ = binary.Write(buf, binary.LittleEndian, v.MaxLat)
+	}
+	return buf.Bytes()
+}
+
+func writeRings(buf *bytes.Buffer, rings [][]Point) {
+	_ = binary.Write(buf, binary.LittleEndian, uint32(len(rings)))
+	for _, ring := range rings {
+		writePoints(buf, ring)
+	}
+}
+
+func writePoints(buf *bytes.Buffer, points []Point) {
+	_ = binary.Write(buf, binary.LittleEndian, uint32(len(points)))
+	for _, p := range points {
+		_ = binary.Write(buf, binary.LittleEndian, p.Lon)
+		_ = binary.Write(buf, binary.LittleEndian, p.Lat)
+	}
+}
+
+// DecodeWKB parses the bytes produced by EncodeWKB back into a Shape.
+func DecodeWKB(b []byte) (Shape, error) {
+	r := bytes.NewReader(b)
+	var order byte
+	if err := binary.Read(r, binary.LittleEndian, &order); err != nil {
+		return nil, err
+	}
+
+	var typ uint32
+	if err := binary.Read(r, binary.LittleEndian, &typ); err != nil {
+		return nil, err
+	}
+
+	switch typ {
+	case wkbLineString:
+		points, err := readPoints(r)
+		if err != nil {
+			return nil, err
+		}
+		return &LineString{Points: points}, nil
+	case wkbPolygon:
+		rings, err := readRings(r)
+		if err != nil {
+			return nil, err
+		}
+		return polygonFromRings(rings)
+	case wkbMultiPolygon:
+		var n uint32
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return nil, err
+		}
+		mp := &MultiPolygon{}
+		for i := uint32(0); i < n; i++ {
+			var ptyp uint32
+			if err := binary.Read(r, binary.LittleEndian, &ptyp); err != nil {
+				return nil, err
+			}
+			rings, err := readRings(r)
+			if err != nil {
+				return nil, err
+			}
+			poly, err := polygonFromRings(rings)
+			if err != nil {
+				return nil, err
+			}
+			mp.Polygons = append(mp.Polygons, poly)
+		}
+		return mp, nil
+	case wkbEnvelope:
+		e := &Envelope{}
+		for _, f := range []*float64{&e.MinLon, &e.MinLat, &e.MaxLon, &e.MaxLat} {
+			if err := binary.Read(r, binary.LittleEndian, f); err != nil {
+				return nil, err
+			}
+		}
+		return e, nil
+	default:
+		return nil, ErrUnknownShapeType
+	}
+}
+
+// ErrEmptyPolygon is returned by DecodeWKB when a Polygon or MultiPolygon
+// entry encodes zero rings, so a malformed doc value fails the single
+// document being evaluated instead of panicking the whole query.
+var ErrEmptyPolygon = fmt.Errorf("wkb polygon has no rings")
+
+func polygonFromRings(rings [][]Point) (*Polygon, error) {
+	if len(rings) == 0 {
+		return nil, ErrEmptyPolygon
+	}
+	p := &Polygon{Outer: rings[0]}
+	if len(rings) > 1 {
+		p.Holes = rings[1:]
+	}
+	return p, nil
+}
+
+func readRings(r *bytes.Reader) ([][]Point, error) {
+	var n uint32
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return nil, err
+	}
+	rings := make([][]Point, n)
+	for i := uint32(0); i < n; i++ {
+		points, err := readPoints(r)
+		if err != nil {
+			return nil, err
+		}
+		rings[i] = points
+	}
+	return rings, nil
+}
+
+func readPoints(r *bytes.Reader) ([]Point, error) {
+	var n uint32
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return nil, err
+	}
+	points := make([]Point, n)
+	for i := uint32(0); i < n; i++ {
+		if err := binary.Read(r, binary.LittleEndian, &points[i].Lon); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(r, binary.LittleEndian, &points[i].Lat); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
@@ -0,0 +1,49 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shape
+
+import "testing"
+
+func TestEncodeDecodeWKBRoundTrip(t *testing.T) {
+	p := &Polygon{Outer: []Point{
+		{Lon: 0, Lat: 0}, {Lon: 0, Lat: 1}, {Lon: 1, Lat: 1}, {Lon: 1, Lat: 0}, {Lon: 0, Lat: 0},
+	}}
+
+	decoded, err := DecodeWKB(EncodeWKB(p))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dp, ok := decoded.(*Polygon)
+	if !ok {
+		t.Fatalf("expected *Polygon, got %T", decoded)
+	}
+	if len(dp.Outer) != len(p.Outer) {
+		t.Fatalf("expected %d outer points, got %d", len(p.Outer), len(dp.Outer))
+	}
+}
+
+func TestDecodeWKBEmptyPolygonReturnsError(t *testing.T) {
+	// a Polygon payload with a ring count of zero must not panic
+	b := []byte{
+		1,          // little-endian byte order marker
+		3, 0, 0, 0, // wkbPolygon
+		0, 0, 0, 0, // ring count = 0
+	}
+
+	_, err := DecodeWKB(b)
+	if err != ErrEmptyPolygon {
+		t.Fatalf("expected ErrEmptyPolygon, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geo
+
+import (
+	"sync"
+
+	"github.com/blugelabs/bluge/numeric"
+	segment "github.com/blugelabs/bluge_segment_api"
+)
+
+// DocValuePoint is a single decoded geo point doc value.
+type DocValuePoint struct {
+	Lon, Lat float32
+}
+
+// DocValueCache bulk-decodes the shift==0 Morton-coded points for a
+// field, for every document in a segment, the first time that field is
+// requested - turning the per-hit VisitDocumentValues/PrefixCoded decode
+// that otherwise dominates query time for large result sets into an O(1)
+// slice lookup on every access after the first. The geo distance, annulus
+// and polygon searchers of one query share this one decode pass when they
+// are given the same cache. SortGeoDistance does not consult the cache; it
+// decodes the terms it is visited with on its own.
+//
+// Callers construct one DocValueCache per query and thread it through
+// every search.SearcherOptions used to build that query's searchers, via
+// Acquire. Release, returned by Acquire, is wired into the acquiring
+// searcher's own Close() so the decoded state is deterministically
+// discarded once every searcher sharing the cache has been closed,
+// without relying on GC finalizer timing.
+type DocValueCache struct {
+	mu      sync.Mutex
+	byField map[string][][]DocValuePoint
+	refs    int
+}
+
+// NewDocValueCache returns an empty DocValueCache.
+func NewDocValueCache() *DocValueCache {
+	return &DocValueCache{byField: make(map[string][][]DocValuePoint)}
+}
+
+// Acquire registers a new owner of this cache (typically one geo
+// searcher) and returns a release function that owner must call exactly
+// once, from its own Close(), when it is done with the cache. Once every
+// acquirer has released, the cache discards its decoded state.
+func (c *DocValueCache) Acquire() (release func()) {
+	c.mu.Lock()
+	c.refs++
+	c.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.refs--
+			if c.refs <= 0 {
+				c.byField = make(map[string][][]DocValuePoint)
+			}
+		})
+	}
+}
+
+// Points returns the points decoded for (field, docNum). The first call
+// for a given field bulk-decodes every document from 0 to docCount-1 on
+// that field in a single pass over dvReader and caches the result as one
+// flat, docNum-indexed slice; every later call against the same field,
+// for any docNum, is then a plain slice index with no further
+// VisitDocumentValues calls.
+func (c *DocValueCache) Points(dvReader segment.DocumentValueReader, docCount uint64,
+	field string, docNum uint64) []DocValuePoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	byDoc, ok := c.byField[field]
+	if !ok {
+		byDoc = make([][]DocValuePoint, docCount)
+		for d := uint64(0); d < docCount; d++ {
+			byDoc[d] = decodePoints(dvReader, field, d)
+		}
+		c.byField[field] = byDoc
+	}
+	if docNum >= uint64(len(byDoc)) {
+		return nil
+	}
+	return byDoc[docNum]
+}
+
+// DecodePoints returns the shift==0 Morton-coded points indexed for
+// docNum on field by decoding directly via dvReader, with no caching.
+// Callers that hold a DocValueCache for the query should prefer Points.
+func DecodePoints(dvReader segment.DocumentValueReader, field string, docNum uint64) []DocValuePoint {
+	return decodePoints(dvReader, field, docNum)
+}
+
+func decodePoints(dvReader segment.DocumentValueReader, field string, docNum uint64) []DocValuePoint {
+	var pts []DocValuePoint
+	_ = dvReader.VisitDocumentValues(docNum, func(visitField string, term []byte) {
+		if visitField != field {
+			return
+		}
+		prefixCoded := numeric.PrefixCoded(term)
+		shift, err := prefixCoded.Shift()
+		if err != nil || shift != 0 {
+			return
+		}
+		i64, err := prefixCoded.Int64()
+		if err != nil {
+			return
+		}
+		pts = append(pts, DocValuePoint{
+			Lon: float32(MortonUnhashLon(uint64(i64))),
+			Lat: float32(MortonUnhashLat(uint64(i64))),
+		})
+	})
+	return pts
+}
@@ -0,0 +1,102 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geo
+
+import (
+	"testing"
+
+	"github.com/blugelabs/bluge/numeric"
+)
+
+// fakeDocValueReader hands back a fixed set of Morton-coded terms per doc
+// number and counts how many times it was visited, so tests can assert
+// the bulk warm-up decodes each document exactly once.
+type fakeDocValueReader struct {
+	field   string
+	terms   map[uint64]int64 // docNum -> Morton hash
+	visited map[uint64]int
+}
+
+func newFakeDocValueReader(field string, terms map[uint64]int64) *fakeDocValueReader {
+	return &fakeDocValueReader{field: field, terms: terms, visited: make(map[uint64]int)}
+}
+
+func (f *fakeDocValueReader) VisitDocumentValues(docNum uint64, visitor func(field string, term []byte)) error {
+	f.visited[docNum]++
+	i64, ok := f.terms[docNum]
+	if !ok {
+		return nil
+	}
+	term, err := numeric.NewPrefixCodedInt64(i64, 0)
+	if err != nil {
+		return err
+	}
+	visitor(f.field, []byte(term))
+	return nil
+}
+
+func TestDocValueCachePointsBulkDecodesEachDocOnce(t *testing.T) {
+	reader := newFakeDocValueReader("loc", map[uint64]int64{
+		0: int64(MortonHash(1, 1)),
+		1: int64(MortonHash(2, 2)),
+		2: int64(MortonHash(3, 3)),
+	})
+	c := NewDocValueCache()
+
+	for _, docNum := range []uint64{2, 0, 1, 2, 0} {
+		pts := c.Points(reader, 3, "loc", docNum)
+		if len(pts) != 1 {
+			t.Fatalf("expected exactly one point for doc %d, got %d", docNum, len(pts))
+		}
+	}
+
+	for docNum, n := range reader.visited {
+		if n != 1 {
+			t.Fatalf("expected doc %d to be visited exactly once by the bulk warm-up, got %d", docNum, n)
+		}
+	}
+}
+
+func TestDocValueCachePointsOutOfRangeDocNum(t *testing.T) {
+	reader := newFakeDocValueReader("loc", map[uint64]int64{0: int64(MortonHash(1, 1))})
+	c := NewDocValueCache()
+
+	if pts := c.Points(reader, 1, "loc", 5); pts != nil {
+		t.Fatalf("expected nil for an out-of-range doc number, got %v", pts)
+	}
+}
+
+func TestDocValueCacheAcquireReleaseDiscardsAfterLastOwner(t *testing.T) {
+	reader := newFakeDocValueReader("loc", map[uint64]int64{0: int64(MortonHash(1, 1))})
+	c := NewDocValueCache()
+
+	releaseA := c.Acquire()
+	releaseB := c.Acquire()
+
+	c.Points(reader, 1, "loc", 0)
+	if len(c.byField) != 1 {
+		t.Fatal("expected the field to be warmed in the cache")
+	}
+
+	releaseA()
+	if len(c.byField) != 1 {
+		t.Fatal("expected state to survive while a second owner still holds the cache")
+	}
+
+	releaseB()
+	if len(c.byField) != 0 {
+		t.Fatal("expected the cache to discard its state once every owner released")
+	}
+}
@@ -0,0 +1,120 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geo
+
+import "math"
+
+// DistanceModel selects the earth model used to compute the distance
+// between two geo points.
+type DistanceModel int
+
+const (
+	// DistanceModelHaversine treats the earth as a perfect sphere. It is
+	// cheap and the default, but can be off by up to ~0.5% near the poles.
+	DistanceModelHaversine DistanceModel = iota
+	// DistanceModelVincenty uses Vincenty's inverse formula against the
+	// WGS-84 ellipsoid. It is roughly twice the cost per candidate but is
+	// accurate to better than a millimeter for non-antipodal points.
+	DistanceModelVincenty
+)
+
+// WGS-84 ellipsoid parameters.
+const (
+	vincentyA = 6378137.0
+	vincentyF = 1 / 298.257223563
+
+	vincentyMaxIterations = 200
+	vincentyConvergence   = 1e-12
+)
+
+// Distance returns the distance in kilometers between (lon1, lat1) and
+// (lon2, lat2) using m. DistanceModelVincenty falls back to
+// DistanceModelHaversine when the iteration fails to converge, which can
+// happen for near-antipodal points.
+func (m DistanceModel) Distance(lon1, lat1, lon2, lat2 float64) float64 {
+	if m == DistanceModelVincenty {
+		if km, ok := vincentyDistanceKM(lon1, lat1, lon2, lat2); ok {
+			return km
+		}
+	}
+	return Haversin(lon1, lat1, lon2, lat2)
+}
+
+// vincentyDistanceKM implements Vincenty's inverse formula for the
+// WGS-84 ellipsoid, returning the distance in kilometers. ok is false if
+// the lambda recurrence fails to converge within vincentyMaxIterations,
+// which happens for points close to antipodal.
+func vincentyDistanceKM(lon1, lat1, lon2, lat2 float64) (km float64, ok bool) {
+	b := vincentyA * (1 - vincentyF)
+
+	phi1 := degToRad(lat1)
+	phi2 := degToRad(lat2)
+	lambdaL := degToRad(lon2 - lon1)
+
+	tanU1 := (1 - vincentyF) * math.Tan(phi1)
+	cosU1 := 1 / math.Sqrt(1+tanU1*tanU1)
+	sinU1 := tanU1 * cosU1
+
+	tanU2 := (1 - vincentyF) * math.Tan(phi2)
+	cosU2 := 1 / math.Sqrt(1+tanU2*tanU2)
+	sinU2 := tanU2 * cosU2
+
+	lambda := lambdaL
+	var sinSigma, cosSigma, sigma, cosSqAlpha, cos2SigmaM float64
+
+	for i := 0; i < vincentyMaxIterations; i++ {
+		sinLambda, cosLambda := math.Sin(lambda), math.Cos(lambda)
+
+		sinSigma = math.Sqrt(math.Pow(cosU2*sinLambda, 2) +
+			math.Pow(cosU1*sinU2-sinU1*cosU2*cosLambda, 2))
+		if sinSigma == 0 {
+			return 0, true // coincident points
+		}
+
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+
+		if cosSqAlpha == 0 {
+			cos2SigmaM = 0 // equatorial line
+		} else {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+
+		c := vincentyF / 16 * cosSqAlpha * (4 + vincentyF*(4-3*cosSqAlpha))
+		lambdaPrev := lambda
+		lambda = lambdaL + (1-c)*vincentyF*sinAlpha*
+			(sigma + c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+
+		if math.Abs(lambda-lambdaPrev) < vincentyConvergence {
+			uSq := cosSqAlpha * (vincentyA*vincentyA - b*b) / (b * b)
+			aCoeff := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+			bCoeff := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+			deltaSigma := bCoeff * sinSigma * (cos2SigmaM + bCoeff/4*
+				(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+					bCoeff/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+			return b * aCoeff * (sigma - deltaSigma) / 1000, true
+		}
+	}
+
+	return 0, false // failed to converge, most likely near-antipodal points
+}
+
+func degToRad(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -0,0 +1,89 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVincentyDistanceKMCoincidentPoints(t *testing.T) {
+	km, ok := vincentyDistanceKM(2.349014, 48.864716, 2.349014, 48.864716)
+	if !ok {
+		t.Fatal("expected convergence for coincident points")
+	}
+	if km != 0 {
+		t.Fatalf("expected 0 distance for coincident points, got %v", km)
+	}
+}
+
+func TestVincentyDistanceKMKnownDistance(t *testing.T) {
+	// Paris to New York. The WGS-84 ellipsoidal (Vincenty) distance is
+	// ~5852km, about 15km more than the ~5837km spherical (Haversine)
+	// great-circle distance for the same two points - exactly the
+	// oblateness error Vincenty exists to correct for.
+	km, ok := vincentyDistanceKM(2.349014, 48.864716, -74.005941, 40.712776)
+	if !ok {
+		t.Fatal("expected convergence for Paris-New York")
+	}
+	if math.Abs(km-5852) > 5 {
+		t.Fatalf("expected ~5852km, got %v", km)
+	}
+}
+
+func TestVincentyDistanceKMAgreesWithHaversineForShortDistances(t *testing.T) {
+	// over a short distance the oblateness of the earth barely matters, so
+	// Vincenty and Haversine should be close (well within Haversine's own
+	// ~0.5% error budget).
+	lon1, lat1 := 0.0, 45.0
+	lon2, lat2 := 0.01, 45.01
+
+	vincenty, ok := vincentyDistanceKM(lon1, lat1, lon2, lat2)
+	if !ok {
+		t.Fatal("expected convergence for short distance")
+	}
+	haversine := Haversin(lon1, lat1, lon2, lat2)
+
+	if math.Abs(vincenty-haversine) > 0.01*haversine {
+		t.Fatalf("expected vincenty (%v) and haversine (%v) to agree within 1%%", vincenty, haversine)
+	}
+}
+
+func TestVincentyDistanceKMFailsToConvergeForAntipodalPoints(t *testing.T) {
+	// near-exact antipodal points are the classic case where Vincenty's
+	// iteration fails to converge.
+	_, ok := vincentyDistanceKM(0, 0, 180, 0)
+	if ok {
+		t.Fatal("expected convergence failure for antipodal points")
+	}
+}
+
+func TestDistanceModelVincentyFallsBackToHaversineOnFailure(t *testing.T) {
+	lon1, lat1 := 0.0, 0.0
+	lon2, lat2 := 180.0, 0.0
+
+	got := DistanceModelVincenty.Distance(lon1, lat1, lon2, lat2)
+	want := Haversin(lon1, lat1, lon2, lat2)
+
+	if got != want {
+		t.Fatalf("expected fallback to Haversine (%v), got %v", want, got)
+	}
+}
+
+func TestDistanceModelHaversineIsTheZeroValue(t *testing.T) {
+	if DistanceModelHaversine != 0 {
+		t.Fatalf("expected DistanceModelHaversine to be the zero value, got %v", DistanceModelHaversine)
+	}
+}
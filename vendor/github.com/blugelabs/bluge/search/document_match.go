@@ -0,0 +1,28 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+// DocumentMatch represents a single hit, carried through scoring and
+// sorting.
+type DocumentMatch struct {
+	// Number is the segment-local document number.
+	Number uint64
+
+	// GeoDistance is set by SortGeoDistance.Value to the distance used to
+	// sort this match, expressed in that SortGeoDistance's configured
+	// Unit, so a caller rendering results (e.g. "3.2 km away") can read it
+	// back without recomputing it from the indexed terms.
+	GeoDistance float64
+}
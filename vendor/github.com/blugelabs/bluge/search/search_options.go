@@ -0,0 +1,36 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import "github.com/blugelabs/bluge/numeric/geo"
+
+// SearcherOptions is threaded through every searcher constructor in the
+// searcher package.
+type SearcherOptions struct {
+	// CacheGeoDocValues opts into sharing GeoDocValueCache across the geo
+	// distance, annulus and polygon searchers built for a single query, so
+	// a field whose doc values are bulk-decoded by one searcher is not
+	// decoded again by another.
+	CacheGeoDocValues bool
+
+	// GeoDocValueCache is consulted (and populated) by the geo searchers
+	// when CacheGeoDocValues is set. Callers that opt in should construct
+	// one geo.DocValueCache per query and share it across every
+	// SearcherOptions value used to build that query's searchers. Each
+	// searcher built with caching enabled acquires its own share of the
+	// cache and releases it from its own Close(), so the cache is
+	// discarded once every searcher sharing it has been closed.
+	GeoDocValueCache *geo.DocValueCache
+}
@@ -0,0 +1,55 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"github.com/blugelabs/bluge/numeric/geo"
+	"github.com/blugelabs/bluge/search"
+	segment "github.com/blugelabs/bluge_segment_api"
+)
+
+// geoPoints returns the decoded points for (field, docNum), consulting
+// the segment-wide bulk decode cache when the caller opted in via
+// options, or decoding directly via dvReader otherwise.
+func geoPoints(indexReader search.Reader, dvReader segment.DocumentValueReader,
+	field string, docNum uint64, options search.SearcherOptions) []geo.DocValuePoint {
+	if options.CacheGeoDocValues && options.GeoDocValueCache != nil {
+		docCount, err := indexReader.Count()
+		if err == nil {
+			return options.GeoDocValueCache.Points(dvReader, docCount, field, docNum)
+		}
+	}
+	return geo.DecodePoints(dvReader, field, docNum)
+}
+
+// wrapWithCacheRelease wraps s so that closing it also releases this
+// searcher's share of a geo.DocValueCache acquired via
+// search.SearcherOptions.GeoDocValueCache. This ties the cache's
+// deterministic release to the same Close() every caller already has to
+// invoke to release the searcher itself, rather than relying on a
+// finalizer running at GC's discretion.
+func wrapWithCacheRelease(s search.Searcher, release func()) search.Searcher {
+	return &cacheReleasingSearcher{Searcher: s, release: release}
+}
+
+type cacheReleasingSearcher struct {
+	search.Searcher
+	release func()
+}
+
+func (s *cacheReleasingSearcher) Close() error {
+	s.release()
+	return s.Searcher.Close()
+}
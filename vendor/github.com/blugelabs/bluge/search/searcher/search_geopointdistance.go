@@ -15,17 +15,20 @@
 package searcher
 
 import (
-	"github.com/blugelabs/bluge/numeric"
 	"github.com/blugelabs/bluge/numeric/geo"
 	"github.com/blugelabs/bluge/search"
 	"github.com/blugelabs/bluge/search/similarity"
 	segment "github.com/blugelabs/bluge_segment_api"
 )
 
+// NewGeoPointDistanceSearcher builds a searcher matching documents within
+// dist meters of (centerLon, centerLat). model selects the earth model
+// used to evaluate the precise distance; the zero value,
+// geo.DistanceModelHaversine, matches prior behavior.
 func NewGeoPointDistanceSearcher(indexReader search.Reader, centerLon,
 	centerLat, dist float64, field string, boost float64, scorer search.Scorer,
 	compScorer search.CompositeScorer, options search.SearcherOptions,
-	precisionStep uint) (search.Searcher, error) {
+	precisionStep uint, model geo.DistanceModel) (search.Searcher, error) {
 	// compute bounding box containing the circle
 	topLeftLon, topLeftLat, bottomRightLon, bottomRightLat, err :=
 		geo.RectFromPointDistance(centerLon, centerLat, dist)
@@ -47,8 +50,13 @@ func NewGeoPointDistanceSearcher(indexReader search.Reader, centerLon,
 	}
 
 	// wrap it in a filtering searcher which checks the actual distance
-	return NewFilteringSearcher(boxSearcher,
-		buildDistFilter(dvReader, centerLon, centerLat, dist)), nil
+	filtered := NewFilteringSearcher(boxSearcher,
+		buildDistFilter(indexReader, dvReader, field, centerLon, centerLat, dist, options, model))
+
+	if options.CacheGeoDocValues && options.GeoDocValueCache != nil {
+		filtered = wrapWithCacheRelease(filtered, options.GeoDocValueCache.Acquire())
+	}
+	return filtered, nil
 }
 
 // boxSearcher builds a searcher for the described bounding box
@@ -95,31 +103,15 @@ func boxSearcher(indexReader search.Reader,
 	return boxSearcher, nil
 }
 
-func buildDistFilter(dvReader segment.DocumentValueReader, centerLon, centerLat, maxDist float64) FilterFunc {
+func buildDistFilter(indexReader search.Reader, dvReader segment.DocumentValueReader, field string,
+	centerLon, centerLat, maxDist float64, options search.SearcherOptions,
+	model geo.DistanceModel) FilterFunc {
 	return func(d *search.DocumentMatch) bool {
-		// check geo matches against all numeric type terms indexed
-		var lons, lats []float64
-		var found bool
-
-		err := dvReader.VisitDocumentValues(d.Number, func(field string, term []byte) {
-			// only consider the values which are shifted 0
-			prefixCoded := numeric.PrefixCoded(term)
-			shift, err := prefixCoded.Shift()
-			if err == nil && shift == 0 {
-				i64, err := prefixCoded.Int64()
-				if err == nil {
-					lons = append(lons, geo.MortonUnhashLon(uint64(i64)))
-					lats = append(lats, geo.MortonUnhashLat(uint64(i64)))
-					found = true
-				}
-			}
-		})
-		if err == nil && found {
-			for i := range lons {
-				dist := geo.Haversin(lons[i], lats[i], centerLon, centerLat)
-				if dist <= maxDist/1000 {
-					return true
-				}
+		points := geoPoints(indexReader, dvReader, field, d.Number, options)
+		for _, p := range points {
+			dist := model.Distance(float64(p.Lon), float64(p.Lat), centerLon, centerLat)
+			if dist <= maxDist/1000 {
+				return true
 			}
 		}
 		return false
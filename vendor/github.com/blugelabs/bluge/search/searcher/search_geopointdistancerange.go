@@ -0,0 +1,87 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"github.com/blugelabs/bluge/numeric/geo"
+	"github.com/blugelabs/bluge/search"
+	segment "github.com/blugelabs/bluge_segment_api"
+)
+
+// NewGeoPointDistanceRangeSearcher builds a searcher which matches documents
+// whose geo point field lies in the annulus between minDist and maxDist
+// (both in meters) from the given center point. When minDist is 0 this
+// behaves identically to NewGeoPointDistanceSearcher.
+func NewGeoPointDistanceRangeSearcher(indexReader search.Reader, centerLon,
+	centerLat, minDist, maxDist float64, field string, boost float64, scorer search.Scorer,
+	compScorer search.CompositeScorer, options search.SearcherOptions,
+	precisionStep uint, model geo.DistanceModel) (search.Searcher, error) {
+	if minDist < 0 || maxDist < 0 || minDist >= maxDist {
+		return nil, geo.ErrInvalidDistance
+	}
+
+	// compute bounding box containing the outer circle
+	topLeftLon, topLeftLat, bottomRightLon, bottomRightLat, err :=
+		geo.RectFromPointDistance(centerLon, centerLat, maxDist)
+	if err != nil {
+		return nil, err
+	}
+
+	// build a searcher for the box
+	boxSearcher, err := boxSearcher(indexReader,
+		topLeftLon, topLeftLat, bottomRightLon, bottomRightLat,
+		field, boost, scorer, compScorer, options, false, precisionStep)
+	if err != nil {
+		return nil, err
+	}
+
+	dvReader, err := indexReader.DocumentValueReader([]string{field})
+	if err != nil {
+		_ = boxSearcher.Close()
+		return nil, err
+	}
+
+	// wrap it in a filtering searcher which checks the actual distance falls
+	// within the requested annulus
+	filtered := NewFilteringSearcher(boxSearcher,
+		buildDistRangeFilter(indexReader, dvReader, field, centerLon, centerLat, minDist, maxDist, options, model))
+
+	if options.CacheGeoDocValues && options.GeoDocValueCache != nil {
+		filtered = wrapWithCacheRelease(filtered, options.GeoDocValueCache.Acquire())
+	}
+	return filtered, nil
+}
+
+func buildDistRangeFilter(indexReader search.Reader, dvReader segment.DocumentValueReader, field string,
+	centerLon, centerLat, minDist, maxDist float64, options search.SearcherOptions,
+	model geo.DistanceModel) FilterFunc {
+	return func(d *search.DocumentMatch) bool {
+		points := geoPoints(indexReader, dvReader, field, d.Number, options)
+		for _, p := range points {
+			dist := model.Distance(float64(p.Lon), float64(p.Lat), centerLon, centerLat)
+			if inAnnulus(dist, minDist, maxDist) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// inAnnulus reports whether distKM, a distance in kilometers, falls within
+// the annulus between minDist and maxDist (both in meters, as accepted by
+// NewGeoPointDistanceRangeSearcher).
+func inAnnulus(distKM, minDist, maxDist float64) bool {
+	return distKM >= minDist/1000 && distKM <= maxDist/1000
+}
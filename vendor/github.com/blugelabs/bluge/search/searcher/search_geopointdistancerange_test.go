@@ -0,0 +1,75 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"testing"
+
+	"github.com/blugelabs/bluge/numeric/geo"
+	"github.com/blugelabs/bluge/search"
+)
+
+func TestInAnnulusBounds(t *testing.T) {
+	// minDist=1000m, maxDist=5000m
+	cases := []struct {
+		distKM float64
+		want   bool
+	}{
+		{0.999, false}, // just inside the inner edge, excluded
+		{1.0, true},    // inner edge is inclusive
+		{3.0, true},    // middle of the annulus
+		{5.0, true},    // outer edge is inclusive
+		{5.001, false}, // just outside the outer edge, excluded
+	}
+	for _, c := range cases {
+		if got := inAnnulus(c.distKM, 1000, 5000); got != c.want {
+			t.Fatalf("inAnnulus(%v, 1000, 5000) = %v, want %v", c.distKM, got, c.want)
+		}
+	}
+}
+
+func TestInAnnulusMinDistZeroMatchesPlainDistanceSearcher(t *testing.T) {
+	// with minDist == 0, inAnnulus must accept everything a plain
+	// "distance <= maxDist" check would, matching the documented
+	// equivalence to NewGeoPointDistanceSearcher.
+	cases := []float64{0, 0.5, 2.0, 5.0}
+	for _, distKM := range cases {
+		plain := distKM <= 5000.0/1000
+		if got := inAnnulus(distKM, 0, 5000); got != plain {
+			t.Fatalf("inAnnulus(%v, 0, 5000) = %v, want %v to match plain distance check", distKM, got, plain)
+		}
+	}
+}
+
+func TestNewGeoPointDistanceRangeSearcherRejectsInvalidDistances(t *testing.T) {
+	cases := []struct {
+		name             string
+		minDist, maxDist float64
+	}{
+		{"negative min", -1, 5000},
+		{"negative max", 1000, -5000},
+		{"min equal to max", 1000, 1000},
+		{"min greater than max", 5000, 1000},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := NewGeoPointDistanceRangeSearcher(nil, 0, 0, c.minDist, c.maxDist,
+				"loc", 1, nil, nil, search.SearcherOptions{}, 0, geo.DistanceModelHaversine)
+			if err != geo.ErrInvalidDistance {
+				t.Fatalf("expected geo.ErrInvalidDistance, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"fmt"
+
+	"github.com/blugelabs/bluge/numeric/geo"
+	"github.com/blugelabs/bluge/search"
+	segment "github.com/blugelabs/bluge_segment_api"
+)
+
+// NewGeoPolygonSearcher builds a searcher which matches documents whose
+// geo point field falls within the (simple, non-self-intersecting) polygon
+// described by points. Longitudes must already be normalized to a single
+// hemisphere by the caller; a polygon that would otherwise cross the
+// antimeridian should be split into multiple polygons before calling this
+// function.
+func NewGeoPolygonSearcher(indexReader search.Reader, points []geo.Point, field string,
+	boost float64, scorer search.Scorer, compScorer search.CompositeScorer,
+	options search.SearcherOptions, precisionStep uint) (search.Searcher, error) {
+	if len(points) < 3 {
+		return nil, fmt.Errorf("geo polygon searcher requires at least 3 points")
+	}
+
+	// close the polygon if the caller did not already do so
+	if points[0] != points[len(points)-1] {
+		points = append(points, points[0])
+	}
+
+	topLeftLon, topLeftLat, bottomRightLon, bottomRightLat := boundingBoxFromPoints(points)
+
+	// build a searcher for the bounding box of the polygon
+	boxSearcher, err := boxSearcher(indexReader,
+		topLeftLon, topLeftLat, bottomRightLon, bottomRightLat,
+		field, boost, scorer, compScorer, options, false, precisionStep)
+	if err != nil {
+		return nil, err
+	}
+
+	dvReader, err := indexReader.DocumentValueReader([]string{field})
+	if err != nil {
+		_ = boxSearcher.Close()
+		return nil, err
+	}
+
+	// wrap it in a filtering searcher which checks actual containment
+	filtered := NewFilteringSearcher(boxSearcher,
+		buildPolygonFilter(indexReader, dvReader, field, points, options))
+
+	if options.CacheGeoDocValues && options.GeoDocValueCache != nil {
+		filtered = wrapWithCacheRelease(filtered, options.GeoDocValueCache.Acquire())
+	}
+	return filtered, nil
+}
+
+// boundingBoxFromPoints computes the axis-aligned bounding box containing
+// all of the given points.
+func boundingBoxFromPoints(points []geo.Point) (topLeftLon, topLeftLat, bottomRightLon, bottomRightLat float64) {
+	topLeftLon, bottomRightLon = points[0].Lon, points[0].Lon
+	topLeftLat, bottomRightLat = points[0].Lat, points[0].Lat
+
+	for _, p := range points[1:] {
+		if p.Lon < topLeftLon {
+			topLeftLon = p.Lon
+		}
+		if p.Lon > bottomRightLon {
+			bottomRightLon = p.Lon
+		}
+		if p.Lat > topLeftLat {
+			topLeftLat = p.Lat
+		}
+		if p.Lat < bottomRightLat {
+			bottomRightLat = p.Lat
+		}
+	}
+	return topLeftLon, topLeftLat, bottomRightLon, bottomRightLat
+}
+
+func buildPolygonFilter(indexReader search.Reader, dvReader segment.DocumentValueReader, field string,
+	points []geo.Point, options search.SearcherOptions) FilterFunc {
+	return func(d *search.DocumentMatch) bool {
+		for _, p := range geoPoints(indexReader, dvReader, field, d.Number, options) {
+			if pointInPolygon(float64(p.Lon), float64(p.Lat), points) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// pointInPolygon reports whether (lon, lat) lies inside the polygon
+// described by points, using the standard ray-casting algorithm. points
+// must already be closed (points[0] == points[len(points)-1]).
+func pointInPolygon(lon, lat float64, points []geo.Point) bool {
+	inside := false
+	for i, j := 0, len(points)-1; i < len(points); j, i = i, i+1 {
+		pi, pj := points[i], points[j]
+		if (pi.Lat > lat) != (pj.Lat > lat) &&
+			lon < (pj.Lon-pi.Lon)*(lat-pi.Lat)/(pj.Lat-pi.Lat)+pi.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,79 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"testing"
+
+	"github.com/blugelabs/bluge/numeric/geo"
+)
+
+func square() []geo.Point {
+	return []geo.Point{
+		{Lon: 0, Lat: 0},
+		{Lon: 0, Lat: 10},
+		{Lon: 10, Lat: 10},
+		{Lon: 10, Lat: 0},
+		{Lon: 0, Lat: 0},
+	}
+}
+
+func TestPointInPolygonInterior(t *testing.T) {
+	if !pointInPolygon(5, 5, square()) {
+		t.Fatal("expected interior point to be contained")
+	}
+}
+
+func TestPointInPolygonExterior(t *testing.T) {
+	cases := []struct{ lon, lat float64 }{
+		{-5, 5}, {15, 5}, {5, -5}, {5, 15}, {20, 20},
+	}
+	for _, c := range cases {
+		if pointInPolygon(c.lon, c.lat, square()) {
+			t.Fatalf("expected (%v, %v) to be outside the polygon", c.lon, c.lat)
+		}
+	}
+}
+
+func TestPointInPolygonConcave(t *testing.T) {
+	// a "C" shape opening to the right; the notch at (7, 5) is outside
+	// even though it is within the overall bounding box
+	notched := []geo.Point{
+		{Lon: 0, Lat: 0},
+		{Lon: 0, Lat: 10},
+		{Lon: 10, Lat: 10},
+		{Lon: 10, Lat: 6},
+		{Lon: 4, Lat: 6},
+		{Lon: 4, Lat: 4},
+		{Lon: 10, Lat: 4},
+		{Lon: 10, Lat: 0},
+		{Lon: 0, Lat: 0},
+	}
+
+	if !pointInPolygon(1, 5, notched) {
+		t.Fatal("expected point in the body of the C to be contained")
+	}
+	if pointInPolygon(7, 5, notched) {
+		t.Fatal("expected point in the notch to be outside")
+	}
+}
+
+func TestBoundingBoxFromPoints(t *testing.T) {
+	minLon, maxLat, maxLon, minLat := boundingBoxFromPoints(square())
+	if minLon != 0 || maxLat != 10 || maxLon != 10 || minLat != 0 {
+		t.Fatalf("unexpected bounding box: minLon=%v maxLat=%v maxLon=%v minLat=%v",
+			minLon, maxLat, maxLon, minLat)
+	}
+}
@@ -0,0 +1,102 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"fmt"
+
+	"github.com/blugelabs/bluge/geo/shape"
+	"github.com/blugelabs/bluge/search"
+	"github.com/blugelabs/bluge/search/similarity"
+	segment "github.com/blugelabs/bluge_segment_api"
+)
+
+// GeoShapePrecision is the default quadtree depth used to tile an indexed
+// or query shape into covering cell terms. shape.CoveringCells stops
+// descending once a cell is fully inside the shape's bounding box, so the
+// interior of a larger shape is covered by coarser ancestor tokens; cells
+// along the edge of the bounding box are still tiled down to this depth
+// (leaf cells are roughly 600m wide at the equator at depth 16) until
+// shape.MaxCoveringCells is reached. Callers indexing unusually small shapes may want a
+// finer value; it must match between indexing and querying a given
+// field.
+const GeoShapePrecision = 16
+
+// NewGeoShapeSearcher builds a searcher which matches documents whose
+// shape field relates to the query shape per relation. It first issues a
+// disjunction over the query shape's covering cell terms to find
+// candidate documents, then filters those candidates by the exact
+// relation against the shape's WKB encoding stored in doc values. A
+// GeoPoint indexed as a degenerate one-cell shape is matched the same way,
+// so NewGeoBoundingBoxSearcher and NewGeoPointDistanceSearcher callers are
+// unaffected by a field also carrying shape data.
+func NewGeoShapeSearcher(indexReader search.Reader, query shape.Shape, relation shape.Relation,
+	field string, boost float64, scorer search.Scorer, compScorer search.CompositeScorer,
+	options search.SearcherOptions) (search.Searcher, error) {
+	cells := shape.CoveringCells(query, GeoShapePrecision)
+	if len(cells) == 0 {
+		return NewMatchNoneSearcher(indexReader)
+	}
+
+	termSearchers := make([]search.Searcher, 0, len(cells))
+	for _, cell := range cells {
+		ts, err := NewTermSearcher(indexReader, cell, field, boost, scorer, compScorer, options)
+		if err != nil {
+			for _, s := range termSearchers {
+				_ = s.Close()
+			}
+			return nil, fmt.Errorf("error building geo shape cell searcher: %w", err)
+		}
+		termSearchers = append(termSearchers, ts)
+	}
+
+	candidates, err := NewDisjunctionSearcher(indexReader,
+		termSearchers, 0, similarity.NewCompositeSumScorer(), options)
+	if err != nil {
+		for _, s := range termSearchers {
+			_ = s.Close()
+		}
+		return nil, err
+	}
+
+	dvReader, err := indexReader.DocumentValueReader([]string{field})
+	if err != nil {
+		_ = candidates.Close()
+		return nil, err
+	}
+
+	return NewFilteringSearcher(candidates, buildShapeFilter(dvReader, field, query, relation)), nil
+}
+
+func buildShapeFilter(dvReader segment.DocumentValueReader, field string,
+	query shape.Shape, relation shape.Relation) FilterFunc {
+	return func(d *search.DocumentMatch) bool {
+		var matched bool
+
+		err := dvReader.VisitDocumentValues(d.Number, func(visitField string, term []byte) {
+			if matched || visitField != field {
+				return
+			}
+			indexed, err := shape.DecodeWKB(term)
+			if err != nil {
+				return
+			}
+			if shape.Relate(indexed, query, relation) {
+				matched = true
+			}
+		})
+		return err == nil && matched
+	}
+}
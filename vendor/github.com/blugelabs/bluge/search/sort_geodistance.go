@@ -0,0 +1,161 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"github.com/blugelabs/bluge/numeric"
+	"github.com/blugelabs/bluge/numeric/geo"
+)
+
+// GeoDistanceMode controls how SortGeoDistance combines multiple indexed
+// points on the same document when computing a distance to sort by.
+type GeoDistanceMode int
+
+const (
+	// GeoDistanceMin sorts by the closest indexed point.
+	GeoDistanceMin GeoDistanceMode = iota
+	// GeoDistanceMax sorts by the farthest indexed point.
+	GeoDistanceMax
+	// GeoDistanceAvg sorts by the average distance of all indexed points.
+	GeoDistanceAvg
+)
+
+// SortGeoDistance sorts matches by their distance from a fixed point,
+// decoding the Morton-coded geo point terms the same way the geo point
+// distance searchers do.
+type SortGeoDistance struct {
+	Field    string
+	Desc     bool
+	Unit     string
+	Mode     GeoDistanceMode
+	Model    geo.DistanceModel
+	Location *geo.Point
+
+	unitMult float64
+	points   []geo.Point
+}
+
+// NewSortGeoDistance constructs a SortGeoDistance ordering hits by their
+// distance from (lon, lat), computed over field and expressed in unit
+// ("km", "mi" or "m") using model. The zero value of geo.DistanceModel,
+// geo.DistanceModelHaversine, is the cheaper default.
+func NewSortGeoDistance(field, unit string, lon, lat float64,
+	mode GeoDistanceMode, model geo.DistanceModel, desc bool) (*SortGeoDistance, error) {
+	unitMult, err := geo.ParseDistanceUnit(unit)
+	if err != nil {
+		return nil, err
+	}
+	rv := &SortGeoDistance{
+		Field:    field,
+		Desc:     desc,
+		Unit:     unit,
+		Mode:     mode,
+		Model:    model,
+		Location: &geo.Point{Lon: lon, Lat: lat},
+		unitMult: unitMult,
+	}
+	return rv, nil
+}
+
+// RequiresDocID says this sort does not need the doc ID.
+func (s *SortGeoDistance) RequiresDocID() bool { return false }
+
+// RequiresScoring says this sort does not need the document score.
+func (s *SortGeoDistance) RequiresScoring() bool { return false }
+
+// RequiresFields says this sort needs doc values for Field.
+func (s *SortGeoDistance) RequiresFields() []string { return []string{s.Field} }
+
+// UpdateVisitor is invoked once per indexed term for the current document,
+// collecting the Morton-coded lon/lat pairs on Field.
+func (s *SortGeoDistance) UpdateVisitor(field string, term []byte) {
+	if field != s.Field {
+		return
+	}
+	prefixCoded := numeric.PrefixCoded(term)
+	shift, err := prefixCoded.Shift()
+	if err != nil || shift != 0 {
+		return
+	}
+	i64, err := prefixCoded.Int64()
+	if err != nil {
+		return
+	}
+	s.points = append(s.points, geo.Point{
+		Lon: geo.MortonUnhashLon(uint64(i64)),
+		Lat: geo.MortonUnhashLat(uint64(i64)),
+	})
+}
+
+// Value computes the distance of a's closest/farthest/average indexed
+// point (per Mode) from Location, writes it onto a.GeoDistance so callers
+// can render it without recomputing, and returns it encoded as a
+// byte-comparable sort key. It resets the per-document accumulator
+// collected by UpdateVisitor.
+func (s *SortGeoDistance) Value(a *DocumentMatch) string {
+	points := s.points
+	s.points = nil
+
+	if len(points) == 0 {
+		return ""
+	}
+
+	// Model.Distance returns kilometers and unitMult is meters per Unit
+	dist := s.Model.Distance(points[0].Lon, points[0].Lat, s.Location.Lon, s.Location.Lat) * 1000 / s.unitMult
+	for _, p := range points[1:] {
+		d := s.Model.Distance(p.Lon, p.Lat, s.Location.Lon, s.Location.Lat) * 1000 / s.unitMult
+		switch s.Mode {
+		case GeoDistanceMin:
+			if d < dist {
+				dist = d
+			}
+		case GeoDistanceMax:
+			if d > dist {
+				dist = d
+			}
+		case GeoDistanceAvg:
+			dist += d
+		}
+	}
+	if s.Mode == GeoDistanceAvg {
+		dist /= float64(len(points))
+	}
+
+	a.GeoDistance = dist
+
+	// encode as a byte-comparable prefix-coded term, the same convention
+	// used for every other numeric value in this codebase (including the
+	// Morton terms decoded above) - a plain "%f" string does not sort in
+	// numeric order (e.g. "10.2" < "9.5").
+	prefixCoded, err := numeric.NewPrefixCodedInt64(numeric.Float64ToInt64(dist), 0)
+	if err != nil {
+		return ""
+	}
+	return string(prefixCoded)
+}
+
+// Descending reports the configured sort direction.
+func (s *SortGeoDistance) Descending() bool { return s.Desc }
+
+// Reverse flips the configured sort direction.
+func (s *SortGeoDistance) Reverse() { s.Desc = !s.Desc }
+
+// Copy returns a copy of this SortGeoDistance, safe for reuse with a
+// different search.
+func (s *SortGeoDistance) Copy() SearchSort {
+	rv := *s
+	rv.points = nil
+	return &rv
+}
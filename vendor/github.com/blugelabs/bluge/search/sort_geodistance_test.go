@@ -0,0 +1,75 @@
+//  Copyright (c) 2020 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"testing"
+
+	"github.com/blugelabs/bluge/numeric"
+	"github.com/blugelabs/bluge/numeric/geo"
+)
+
+// TestSortGeoDistanceValueOrdersNumerically guards against a regression
+// where Value formatted the distance with "%f", which does not sort in
+// numeric order across a power-of-ten boundary (e.g. "10.2" < "9.5").
+func TestSortGeoDistanceValueOrdersNumerically(t *testing.T) {
+	s, err := NewSortGeoDistance("loc", "km", 0, 0, GeoDistanceMin, geo.DistanceModelHaversine, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	near := valueForPoint(t, s, 0, 0.0001) // ~11m away
+	far := valueForPoint(t, s, 0, 1)       // ~111km away
+
+	if !(near < far) {
+		t.Fatalf("expected nearer point's sort value (%q) to sort before farther point's (%q)", near, far)
+	}
+}
+
+func valueForPoint(t *testing.T, s *SortGeoDistance, lon, lat float64) string {
+	t.Helper()
+	return s.Value(visit(t, s, lon, lat))
+}
+
+// TestSortGeoDistanceValueExposesDistanceOnDocumentMatch guards against a
+// regression where Value returned only an opaque sort key, leaving a
+// caller that wants to render "X km away" with no way to read the
+// distance back without recomputing it.
+func TestSortGeoDistanceValueExposesDistanceOnDocumentMatch(t *testing.T) {
+	s, err := NewSortGeoDistance("loc", "km", 0, 0, GeoDistanceMin, geo.DistanceModelHaversine, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	a := visit(t, s, 0, 1) // ~111km away
+	s.Value(a)
+
+	if a.GeoDistance < 100 || a.GeoDistance > 120 {
+		t.Fatalf("expected GeoDistance to be populated with ~111km, got %v", a.GeoDistance)
+	}
+}
+
+func visit(t *testing.T, s *SortGeoDistance, lon, lat float64) *DocumentMatch {
+	t.Helper()
+
+	i64 := int64(geo.MortonHash(lon, lat))
+	term, err := numeric.NewPrefixCodedInt64(i64, 0)
+	if err != nil {
+		t.Fatalf("unexpected error encoding term: %v", err)
+	}
+
+	s.UpdateVisitor("loc", term)
+	return &DocumentMatch{Number: 0}
+}